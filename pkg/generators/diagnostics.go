@@ -0,0 +1,58 @@
+package generators
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// errLocationRE matches the "template: name:line:" or "template: name:line:col:" location prefix that Go's
+// text/template attaches to parse and execute errors.
+var errLocationRE = regexp.MustCompile(`^template: [^:]+:(\d+)(?::\d+)?:`)
+
+// withDiagnostics wraps a template parse/execute error with a snippet of content surrounding the line the
+// error location prefix points to, so failures such as an undefined function or key are actionable rather than
+// a bare message.
+func withDiagnostics(content string, err error) error {
+	match := errLocationRE.FindStringSubmatch(err.Error())
+	if match == nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	line, convErr := strconv.Atoi(match[1])
+	if convErr != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return fmt.Errorf("%w\n%s", err, snippet(content, line))
+}
+
+// snippet returns the target line of content plus one line of surrounding context on either side, each
+// prefixed with its line number and a marker on the offending line.
+func snippet(content string, target int) string {
+	lines := strings.Split(content, "\n")
+
+	start := target - 1
+	if start < 1 {
+		start = 1
+	}
+
+	end := target + 1
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	var b strings.Builder
+
+	for i := start; i <= end; i++ {
+		marker := "  "
+		if i == target {
+			marker = "> "
+		}
+
+		fmt.Fprintf(&b, "%s%d | %s\n", marker, i, lines[i-1])
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,128 @@
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sprigFuncs returns the extended standard function library registered by WithSprigFuncs: string manipulation,
+// collection helpers, and encoding helpers commonly needed when generating non-Go/Terraform output, modeled
+// after Masterminds/sprig.
+//
+// env and expandenv are intentionally not provided: exposing the process environment to templates is unsafe
+// when template content comes from an untrusted source, the same precaution Helm's engine takes.
+func sprigFuncs() template.FuncMap {
+	return template.FuncMap{
+		"trim":      strings.TrimSpace,
+		"indent":    indent,
+		"nindent":   nindent,
+		"replace":   func(old, newStr, s string) string { return strings.ReplaceAll(s, old, newStr) },
+		"contains":  func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix": func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"list":      list,
+		"dict":      dict,
+		"first":     first,
+		"last":      last,
+		"default":   defaultValue,
+		"toJson":    toJSON,
+		"toYaml":    toYAML,
+		"quote":     quote,
+	}
+}
+
+// indent prepends spaces worth of indentation to every line of s.
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// nindent is indent, but also prefixes the result with a newline, for embedding a block under a key.
+func nindent(spaces int, s string) string {
+	return "\n" + indent(spaces, s)
+}
+
+// list collects its arguments into a slice, for building ad-hoc collections inside a template.
+func list(items ...any) []any {
+	return items
+}
+
+// dict builds a map[string]any from alternating key/value arguments.
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict requires an even number of arguments, got %d", len(pairs))
+	}
+
+	d := make(map[string]any, len(pairs)/2)
+
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict keys must be strings, got %T", pairs[i])
+		}
+
+		d[key] = pairs[i+1]
+	}
+
+	return d, nil
+}
+
+// first returns the first element of items, or nil if items is empty.
+func first(items []any) any {
+	if len(items) == 0 {
+		return nil
+	}
+
+	return items[0]
+}
+
+// last returns the last element of items, or nil if items is empty.
+func last(items []any) any {
+	if len(items) == 0 {
+		return nil
+	}
+
+	return items[len(items)-1]
+}
+
+// defaultValue returns def when val is nil or an empty string, otherwise it returns val.
+func defaultValue(def, val any) any {
+	if val == nil {
+		return def
+	}
+
+	if s, ok := val.(string); ok && s == "" {
+		return def
+	}
+
+	return val
+}
+
+// toJSON marshals v to a compact JSON string.
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	return string(b), nil
+}
+
+// toYAML marshals v to a YAML string, with the trailing newline trimmed so it can be indented by the caller.
+func toYAML(v any) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// quote wraps v, formatted as a string, in double quotes.
+func quote(v any) string {
+	return fmt.Sprintf("%q", fmt.Sprintf("%v", v))
+}
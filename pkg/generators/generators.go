@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
+	"runtime"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/ettle/strcase"
@@ -17,13 +20,39 @@ import (
 // ErrUnsupportedFileType is an error indicating that the file type is not supported.
 var ErrUnsupportedFileType = errors.New("unsupported file type")
 
+// ErrTemplateNotFound is an error indicating that no template is registered under the given name.
+var ErrTemplateNotFound = errors.New("template not found")
+
 // FormaterByExtMap is a map associating file extensions with formatting functions.
 type FormaterByExtMap map[string]func(outputFile string) error
 
+// WriteFile persists data as the contents of the file at path. It is the extension point buildFile uses to
+// write rendered output; see WithWriteFile and the MapSink/DryRunSink/DiffSink built-in sinks.
+type WriteFile func(path string, data []byte) error
+
+// defaultWriteFile writes data to path on the local filesystem, creating any missing parent directories.
+func defaultWriteFile(outputPath string, data []byte) error {
+	if err := os.MkdirAll(path.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
 // TemplateGenerator struct holds the configuration and methods for generating templates.
 type TemplateGenerator struct {
-	funcs         template.FuncMap
-	formaterByExt FormaterByExtMap
+	funcs              template.FuncMap
+	formaterByExt      FormaterByExtMap
+	templatesMap       map[string]string
+	concurrency        int
+	skipUnsupportedExt bool
+	strict             bool
+	bufferPool         sync.Pool
+	writeFile          WriteFile
 }
 
 // Option is a functional option to configure TemplateGenerator.
@@ -45,6 +74,12 @@ func NewTemplateGenerator(opts ...Option) *TemplateGenerator {
 			".go": utils.GoFormat,
 			".tf": utils.TerraformFormat,
 		},
+		concurrency:        runtime.NumCPU(),
+		skipUnsupportedExt: true,
+		bufferPool: sync.Pool{
+			New: func() any { return new(bytes.Buffer) },
+		},
+		writeFile: defaultWriteFile,
 	}
 
 	for _, opt := range opts {
@@ -72,9 +107,160 @@ func WithExtraFormaterByExt(formaterByExt FormaterByExtMap) Option {
 	}
 }
 
+// WithSprigFuncs registers an extended, Sprig-style standard function library (string manipulation, collection
+// helpers, and encoding helpers) alongside the built-in casing helpers registered by NewTemplateGenerator.
+func WithSprigFuncs() Option {
+	return func(tg *TemplateGenerator) {
+		for k, v := range sprigFuncs() {
+			tg.funcs[k] = v
+		}
+	}
+}
+
+// WithoutDefaultFuncs clears every function registered by NewTemplateGenerator (the casing helpers), letting
+// callers start from an empty FuncMap, e.g. when generating non-Go/Terraform output.
+func WithoutDefaultFuncs() Option {
+	return func(tg *TemplateGenerator) {
+		tg.funcs = template.FuncMap{}
+	}
+}
+
+// WithConcurrency sets how many files GenerateFiles renders and writes in parallel. It defaults to
+// runtime.NumCPU(). Values less than 1 are ignored.
+func WithConcurrency(n int) Option {
+	return func(tg *TemplateGenerator) {
+		if n > 0 {
+			tg.concurrency = n
+		}
+	}
+}
+
+// WithSkipUnsupportedExt controls whether GenerateFiles treats ErrUnsupportedFileType as fatal. It defaults to
+// true, i.e. files whose extension has no registered formater are written as-is and skipped rather than
+// failing the whole batch; pass false to make an unrecognized extension a hard error.
+func WithSkipUnsupportedExt(skip bool) Option {
+	return func(tg *TemplateGenerator) {
+		tg.skipUnsupportedExt = skip
+	}
+}
+
+// WithWriteFile overrides how buildFile persists rendered output, replacing the default (write to the local
+// filesystem, creating parent directories as needed). Use this to plug in MapSink, DryRunSink, DiffSink, or a
+// custom sink.
+func WithWriteFile(writeFile WriteFile) Option {
+	return func(tg *TemplateGenerator) {
+		tg.writeFile = writeFile
+	}
+}
+
+// WithStrict makes template execution fail when the data supplied is missing a field or map key referenced by
+// the template, by setting the "missingkey=error" template option, instead of silently rendering "<no value>".
+func WithStrict() Option {
+	return func(tg *TemplateGenerator) {
+		tg.strict = true
+	}
+}
+
+// loadConfig holds the configuration built from LoadOptions passed to LoadFS.
+type loadConfig struct {
+	rootDir    string
+	trimSuffix string
+}
+
+// LoadOption is a functional option to configure LoadFS behavior.
+type LoadOption func(*loadConfig)
+
+// WithRootDir sets the directory within fsys to walk, stripping it from the keys used to register templates.
+func WithRootDir(dir string) LoadOption {
+	return func(lc *loadConfig) {
+		lc.rootDir = dir
+	}
+}
+
+// WithTrimSuffix sets a suffix to trim from file names when registering templates, e.g. ".tmpl".
+func WithTrimSuffix(suffix string) LoadOption {
+	return func(lc *loadConfig) {
+		lc.trimSuffix = suffix
+	}
+}
+
+// LoadFS walks fsys and registers every file found as a named template, keyed by its base name, or by its
+// path relative to WithRootDir when that option is provided. WithTrimSuffix is applied to the resulting key
+// afterwards. Loaded templates can later be rendered via AsString/MustString, or referenced from other
+// templates using the include/tpl funcs.
+func (tg *TemplateGenerator) LoadFS(fsys fs.FS, opts ...LoadOption) error {
+	lc := &loadConfig{}
+
+	for _, opt := range opts {
+		opt(lc)
+	}
+
+	root := lc.rootDir
+	if root == "" {
+		root = "."
+	}
+
+	if tg.templatesMap == nil {
+		tg.templatesMap = map[string]string{}
+	}
+
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		name := path.Base(p)
+		if lc.rootDir != "" {
+			name = strings.TrimPrefix(strings.TrimPrefix(p, lc.rootDir), "/")
+		}
+
+		if lc.trimSuffix != "" {
+			name = strings.TrimSuffix(name, lc.trimSuffix)
+		}
+
+		tg.templatesMap[name] = string(content)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// AsString renders the template registered under name (via LoadFS) using data and returns the result.
+func (tg *TemplateGenerator) AsString(name string, data any) (string, error) {
+	content, ok := tg.templatesMap[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	return tg.Build(data, name, content)
+}
+
+// MustString is like AsString but panics instead of returning an error.
+func (tg *TemplateGenerator) MustString(name string, data any) string {
+	output, err := tg.AsString(name, data)
+	if err != nil {
+		panic(err)
+	}
+
+	return output
+}
+
 // Build executes the provided templateContent using the data supplied and returns the resulting string.
 func (tg *TemplateGenerator) Build(data any, templateName, templateContent string) (string, error) {
-	tmpl, err := tg.buildAndParseTemplate(templateName, templateContent)
+	tmpl, err := tg.buildAndParseTemplate(templateName, templateContent, tg.templatesMap)
 	if err != nil {
 		return "", fmt.Errorf("%w", err)
 	}
@@ -83,7 +269,7 @@ func (tg *TemplateGenerator) Build(data any, templateName, templateContent strin
 
 	err = tmpl.Execute(&output, data)
 	if err != nil {
-		return "", fmt.Errorf("%w", err)
+		return "", withDiagnostics(templateContent, err)
 	}
 
 	return output.String(), nil
@@ -103,24 +289,21 @@ func (tg *TemplateGenerator) GenerateFile(templatesMap map[string]string, fileNa
 		tmpl = fileTmpl
 	}
 
-	if err := tg.buildFile(data, tmplName, tmpl, outputFile); err != nil {
-		return fmt.Errorf("%w", err)
-	}
-
-	if err := tg.formatFileBasedOnExt(fileName, outputFile); err != nil {
+	if err := tg.buildFile(data, tmplName, tmpl, outputFile, fileName, templatesMap); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
 	return nil
 }
 
-// GenerateFiles generates multiple files using the provided templates and data, and writes the outputs to the specified
-// output directory.
+// GenerateFiles generates multiple files using the provided templates and data, and writes the outputs to the
+// specified output directory. Files are rendered and written concurrently, up to the concurrency configured via
+// WithConcurrency (runtime.NumCPU() by default). Failures across files are aggregated with errors.Join, so
+// callers can errors.Is/errors.As individual failures, including ErrUnsupportedFileType.
 func (tg *TemplateGenerator) GenerateFiles(
 	defaultTemplatesMap map[string]string, templatesMap map[string]string, data any, output string,
 ) error {
 	mergedTemplates := map[string]string{}
-	var errs []error
 
 	for filename, tmpl := range defaultTemplatesMap {
 		mergedTemplates[filename] = tmpl
@@ -130,69 +313,193 @@ func (tg *TemplateGenerator) GenerateFiles(
 		mergedTemplates[filename] = tmpl
 	}
 
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, tg.concurrency)
+	)
+
 	for filename, fileTmpl := range mergedTemplates {
-		tmplName := fmt.Sprintf("%s-template", strings.ReplaceAll(filename, ".", "-"))
+		filename, fileTmpl := filename, fileTmpl
 
-		outputFile := path.Join(output, filename)
+		sem <- struct{}{}
+		wg.Add(1)
 
-		err := tg.buildFile(data, tmplName, fileTmpl, outputFile)
-		if err != nil {
-			errs = append(errs, err)
-		}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
 
-		err = tg.formatFileBasedOnExt(filename, outputFile)
-		if err != nil && !errors.Is(err, ErrUnsupportedFileType) {
-			errs = append(errs, err)
-		}
+			if err := tg.generateOneFile(filename, fileTmpl, output, mergedTemplates, data); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("%v", errs)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// generateOneFile builds, formats, and writes a single file on behalf of GenerateFiles.
+func (tg *TemplateGenerator) generateOneFile(
+	filename, fileTmpl, output string, associated map[string]string, data any,
+) error {
+	tmplName := fmt.Sprintf("%s-template", strings.ReplaceAll(filename, ".", "-"))
+	outputFile := path.Join(output, filename)
+
+	if err := tg.buildFile(data, tmplName, fileTmpl, outputFile, filename, associated); err != nil {
+		if errors.Is(err, ErrUnsupportedFileType) && tg.skipUnsupportedExt {
+			return nil
+		}
+
+		return fmt.Errorf("%w", err)
 	}
 
 	return nil
 }
 
-// buildAndParseTemplate builds and parses a template with the given name and content.
-func (tg *TemplateGenerator) buildAndParseTemplate(name, content string) (*template.Template, error) {
-	tmpl, err := template.New(name).Funcs(tg.funcs).Parse(content)
-	if err != nil {
-		return nil, fmt.Errorf("%w", err)
+// buildAndParseTemplate builds and parses a template with the given name and content. Templates in associated
+// are parsed into the same template set lazily, the first time include actually references them, rather than
+// upfront: with GenerateFiles passing the whole batch as associated, eagerly parsing every entry would make one
+// malformed template fail every file in the batch instead of just the ones that reference it. Per-execution
+// include/tpl funcs are bound against that set so templates can reference each other, mirroring how Helm's
+// engine late-binds these placeholders for each render.
+func (tg *TemplateGenerator) buildAndParseTemplate(name, content string, associated map[string]string) (*template.Template, error) {
+	root := template.New(name)
+
+	funcs := template.FuncMap{}
+	for k, v := range tg.funcs {
+		funcs[k] = v
 	}
 
-	return tmpl, nil
+	funcs["include"] = func(name string, data any) (string, error) {
+		if root.Lookup(name) == nil {
+			src, ok := associated[name]
+			if !ok {
+				return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+			}
+
+			if _, err := root.New(name).Parse(src); err != nil {
+				return "", withDiagnostics(src, err)
+			}
+		}
+
+		var buf bytes.Buffer
+
+		if err := root.ExecuteTemplate(&buf, name, data); err != nil {
+			return "", fmt.Errorf("%w", err)
+		}
+
+		return buf.String(), nil
+	}
+
+	funcs["tpl"] = func(content string, data any) (string, error) {
+		t, err := root.Clone()
+		if err != nil {
+			return "", fmt.Errorf("%w", err)
+		}
+
+		t, err = t.New("tpl").Parse(content)
+		if err != nil {
+			return "", fmt.Errorf("%w", err)
+		}
+
+		var buf bytes.Buffer
+
+		if err = t.ExecuteTemplate(&buf, "tpl", data); err != nil {
+			return "", fmt.Errorf("%w", err)
+		}
+
+		return buf.String(), nil
+	}
+
+	root.Funcs(funcs)
+
+	if tg.strict {
+		root.Option("missingkey=error")
+	}
+
+	if _, err := root.Parse(content); err != nil {
+		return nil, withDiagnostics(content, err)
+	}
+
+	return root, nil
 }
 
-// buildFile builds a file from the given data and template content, writing it to the specified output path.
-func (tg *TemplateGenerator) buildFile(data any, templateName, templateContent, outputPath string) error {
-	tmpl, err := tg.buildAndParseTemplate(templateName, templateContent)
+// buildFile builds a file from the given data and template content, formats it based on fileName's extension,
+// and writes the result to the specified output path. associated holds the other templates loaded alongside
+// this one, made available to include/tpl. The template is rendered into a pooled buffer and formatted there
+// first, so writeFile only ever sees final bytes: a rendering or formatting failure never leaves a partially
+// written or unformatted file behind, and sinks such as MapSink/DiffSink that never touch outputPath on disk
+// still receive properly formatted Go/Terraform output.
+func (tg *TemplateGenerator) buildFile(data any, templateName, templateContent, outputPath, fileName string, associated map[string]string) error {
+	tmpl, err := tg.buildAndParseTemplate(templateName, templateContent, associated)
 	if err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
-	output, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("%w", err)
+	buf, _ := tg.bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	defer tg.bufferPool.Put(buf)
+
+	if err := tmpl.Execute(buf, data); err != nil {
+		return withDiagnostics(templateContent, err)
 	}
-	defer output.Close()
 
-	err = tmpl.Execute(output, data)
-	if err != nil {
+	output, formatErr := tg.formatBytes(fileName, buf.Bytes())
+	if formatErr != nil && !errors.Is(formatErr, ErrUnsupportedFileType) {
+		return fmt.Errorf("%w", formatErr)
+	}
+
+	if err := tg.writeFile(outputPath, output); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
-	return nil
+	return formatErr
 }
 
-// formatFileBasedOnExt formats a file based on its extension using the corresponding formatter.
-func (tg *TemplateGenerator) formatFileBasedOnExt(fileName, outputFile string) (err error) {
+// formatBytes runs the formater registered for fileName's extension against data and returns the formatted
+// bytes. Formaters operate on a file path rather than in-memory content, so data is round-tripped through a
+// temporary file on the real filesystem; this keeps formatting working regardless of which WriteFile sink is
+// configured. If no formater is registered for the extension, data is returned unchanged alongside
+// ErrUnsupportedFileType.
+func (tg *TemplateGenerator) formatBytes(fileName string, data []byte) ([]byte, error) {
 	ext := path.Ext(fileName)
 
-	if formater, ok := tg.formaterByExt[ext]; ok {
-		err = formater(outputFile)
-	} else {
-		err = ErrUnsupportedFileType
+	formater, ok := tg.formaterByExt[ext]
+	if !ok {
+		return data, ErrUnsupportedFileType
+	}
+
+	tmpFile, err := os.CreateTemp("", "*"+ext)
+	if err != nil {
+		return data, fmt.Errorf("%w", err)
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+
+		return data, fmt.Errorf("%w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return data, fmt.Errorf("%w", err)
+	}
+
+	if err := formater(tmpFile.Name()); err != nil {
+		return data, fmt.Errorf("%w", err)
+	}
+
+	formatted, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return data, fmt.Errorf("%w", err)
 	}
 
-	return err
+	return formatted, nil
 }
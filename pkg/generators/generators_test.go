@@ -2,9 +2,12 @@ package generators
 
 import (
 	_ "embed"
+	"errors"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"text/template"
 
 	"github.com/stretchr/testify/require"
@@ -111,8 +114,22 @@ func TestBuild(t *testing.T) {
 				templateName:    "invalid",
 				templateContent: "{{ InvalidFunction .Name }}",
 			},
-			want:           "",
-			expectedErrMsg: "template: invalid:1: function \"InvalidFunction\" not defined",
+			want: "",
+			expectedErrMsg: "template: invalid:1: function \"InvalidFunction\" not defined" +
+				"\n> 1 | {{ InvalidFunction .Name }}",
+		},
+		{
+			name: "invalid template on a middle line shows symmetric context",
+			args: args{
+				data:            map[string]any{"Name": "John", "Age": 30},
+				templateName:    "invalid",
+				templateContent: "Line one\n{{ InvalidFunction .Name }}\nLine three\nLine four",
+			},
+			want: "",
+			expectedErrMsg: "template: invalid:2: function \"InvalidFunction\" not defined" +
+				"\n  1 | Line one" +
+				"\n> 2 | {{ InvalidFunction .Name }}" +
+				"\n  3 | Line three",
 		},
 	}
 
@@ -274,6 +291,73 @@ func TestGenerateFiles(t *testing.T) {
 	}
 }
 
+func TestGenerateFilesIsolatesParseFailures(t *testing.T) {
+	testOutput := "./testoutput"
+	_ = os.MkdirAll(testOutput, os.ModePerm)
+
+	defer func() {
+		_ = os.RemoveAll(testOutput)
+	}()
+
+	templates := map[string]string{
+		"good.txt": "Hello, {{.Name}}!",
+		"bad.txt":  "{{ .Name",
+	}
+
+	err := NewTemplateGenerator().GenerateFiles(templates, nil, struct{ Name string }{"John"}, testOutput)
+	require.Error(t, err)
+
+	data, readErr := os.ReadFile(path.Join(testOutput, "good.txt"))
+	require.NoError(t, readErr)
+	require.Equal(t, "Hello, John!", string(data))
+}
+
+func TestGenerateFilesWithSkipUnsupportedExt(t *testing.T) {
+	testOutput := "./testoutput"
+	_ = os.MkdirAll(testOutput, os.ModePerm)
+
+	defer func() {
+		_ = os.RemoveAll(testOutput)
+	}()
+
+	templates := map[string]string{
+		"template.txt": "Hello, {{.Name}}!",
+		"other.txt":    "Bye, {{.Name}}!",
+	}
+
+	t.Run("skipped by default", func(t *testing.T) {
+		err := NewTemplateGenerator(WithConcurrency(1)).
+			GenerateFiles(templates, nil, struct{ Name string }{"John"}, testOutput)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("fatal when disabled", func(t *testing.T) {
+		err := NewTemplateGenerator(WithSkipUnsupportedExt(false)).
+			GenerateFiles(templates, nil, struct{ Name string }{"John"}, testOutput)
+
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUnsupportedFileType))
+	})
+}
+
+func TestWithStrict(t *testing.T) {
+	t.Run("missing field is an error instead of <no value>", func(t *testing.T) {
+		_, err := NewTemplateGenerator(WithStrict()).
+			Build(map[string]any{"Name": "John"}, "invalid", "{{ .MissingField }}")
+
+		require.Error(t, err)
+	})
+
+	t.Run("default behavior is unaffected", func(t *testing.T) {
+		got, err := NewTemplateGenerator().
+			Build(map[string]any{"Name": "John"}, "invalid", "{{ .MissingField }}")
+
+		require.NoError(t, err)
+		require.Equal(t, "<no value>", got)
+	})
+}
+
 func TestWithFuncs(t *testing.T) {
 	extraFuncs := template.FuncMap{
 		"CustomFunc1": func() string { return "CustomFunc1" },
@@ -305,3 +389,198 @@ func TestWithExtraFormaterByExt(t *testing.T) {
 		require.Equal(t, expectedFormatter(""), actualFormatter(""))
 	}
 }
+
+func TestWithSprigFuncs(t *testing.T) {
+	tests := []struct {
+		name            string
+		templateContent string
+		want            string
+	}{
+		{name: "trim", templateContent: "{{ trim .Name }}", want: "World"},
+		{name: "indent", templateContent: "{{ indent 2 .Name }}", want: "  World"},
+		{name: "nindent", templateContent: "{{ nindent 2 .Name }}", want: "\n  World"},
+		{name: "replace", templateContent: `{{ replace "o" "0" .Name }}`, want: "W0rld"},
+		{name: "contains", templateContent: `{{ contains "orl" .Name }}`, want: "true"},
+		{name: "hasPrefix", templateContent: `{{ hasPrefix "Wor" .Name }}`, want: "true"},
+		{name: "list and first", templateContent: `{{ first (list .Name "Other") }}`, want: "World"},
+		{name: "dict", templateContent: `{{ (dict "k" .Name).k }}`, want: "World"},
+		{name: "default on empty", templateContent: `{{ default "fallback" "" }}`, want: "fallback"},
+		{name: "toJson", templateContent: `{{ toJson .Name }}`, want: `"World"`},
+		{name: "quote", templateContent: `{{ quote .Name }}`, want: `"World"`},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewTemplateGenerator(WithSprigFuncs()).
+				Build(struct{ Name string }{Name: "World"}, "example", tc.templateContent)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestWithoutDefaultFuncs(t *testing.T) {
+	_, err := NewTemplateGenerator(WithoutDefaultFuncs()).
+		Build(struct{ Name string }{Name: "my-name"}, "example", "{{ToCamel .Name}}")
+
+	require.Error(t, err)
+}
+
+func TestIncludeAndTpl(t *testing.T) {
+	t.Run("include renders another loaded template", func(t *testing.T) {
+		tg := NewTemplateGenerator()
+
+		require.NoError(t, tg.LoadFS(fstest.MapFS{
+			"name.tmpl": &fstest.MapFile{Data: []byte("{{.Name}}")},
+		}))
+
+		got, err := tg.Build(
+			struct{ Name string }{Name: "World"}, "greeting", `Hello, {{ include "name.tmpl" . }}!`)
+		require.NoError(t, err)
+		require.Equal(t, "Hello, World!", got)
+	})
+
+	t.Run("tpl parses and executes an ad-hoc template string", func(t *testing.T) {
+		got, err := NewTemplateGenerator().Build(
+			struct{ Name string }{Name: "World"}, "greeting", `{{ tpl "Hello, {{.Name}}!" . }}`)
+		require.NoError(t, err)
+		require.Equal(t, "Hello, World!", got)
+	})
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/greeting.txt.tmpl": &fstest.MapFile{Data: []byte("Hello, {{.Name}}!")},
+		"templates/farewell.txt.tmpl": &fstest.MapFile{Data: []byte("Bye, {{.Name}}!")},
+	}
+
+	t.Run("default keys by base name", func(t *testing.T) {
+		tg := NewTemplateGenerator()
+
+		require.NoError(t, tg.LoadFS(fsys))
+
+		got, err := tg.AsString("greeting.txt.tmpl", struct{ Name string }{Name: "World"})
+		require.NoError(t, err)
+		require.Equal(t, "Hello, World!", got)
+	})
+
+	t.Run("WithRootDir and WithTrimSuffix key by stripped relative path", func(t *testing.T) {
+		tg := NewTemplateGenerator()
+
+		require.NoError(t, tg.LoadFS(fsys, WithRootDir("templates"), WithTrimSuffix(".tmpl")))
+
+		got := tg.MustString("greeting.txt", struct{ Name string }{Name: "World"})
+		require.Equal(t, "Hello, World!", got)
+	})
+
+	t.Run("AsString on an unknown name errors", func(t *testing.T) {
+		tg := NewTemplateGenerator()
+
+		require.NoError(t, tg.LoadFS(fsys))
+
+		_, err := tg.AsString("missing.txt.tmpl", nil)
+		require.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+}
+
+func TestWithWriteFile(t *testing.T) {
+	t.Run("MapSink collects rendered output in memory", func(t *testing.T) {
+		sink := NewMapSink()
+
+		tg := NewTemplateGenerator(WithWriteFile(sink.WriteFile))
+
+		err := tg.GenerateFile(
+			map[string]string{"greeting.txt": "Hello, {{.Name}}!"},
+			"greeting.txt", "", "out/greeting.txt", struct{ Name string }{Name: "World"})
+		require.ErrorIs(t, err, ErrUnsupportedFileType)
+
+		require.Equal(t, "Hello, World!", sink.Files["out/greeting.txt"])
+	})
+
+	t.Run("DryRunSink records intended paths and byte counts", func(t *testing.T) {
+		sink := NewDryRunSink()
+
+		tg := NewTemplateGenerator(WithWriteFile(sink.WriteFile))
+
+		err := tg.GenerateFile(
+			map[string]string{"greeting.txt": "Hello, {{.Name}}!"},
+			"greeting.txt", "", "out/greeting.txt", struct{ Name string }{Name: "World"})
+		require.ErrorIs(t, err, ErrUnsupportedFileType)
+
+		require.Equal(t, []DryRunEntry{{Path: "out/greeting.txt", Bytes: len("Hello, World!")}}, sink.Entries)
+	})
+
+	t.Run("DiffSink reports new files as changed", func(t *testing.T) {
+		sink := NewDiffSink()
+
+		tg := NewTemplateGenerator(WithWriteFile(sink.WriteFile))
+
+		err := tg.GenerateFile(
+			map[string]string{"greeting.txt": "Hello, {{.Name}}!"},
+			"greeting.txt", "", "out/greeting.txt", struct{ Name string }{Name: "World"})
+		require.ErrorIs(t, err, ErrUnsupportedFileType)
+
+		require.Equal(t, []DiffEntry{{Path: "out/greeting.txt", Exists: false, Changed: true}}, sink.Changes)
+	})
+
+	t.Run("MapSink receives formatted content for extensions with a registered formater", func(t *testing.T) {
+		upperFormater := FormaterByExtMap{
+			".custom": func(outputFile string) error {
+				data, err := os.ReadFile(outputFile)
+				if err != nil {
+					return err
+				}
+
+				return os.WriteFile(outputFile, []byte(strings.ToUpper(string(data))), 0o644)
+			},
+		}
+
+		sink := NewMapSink()
+
+		tg := NewTemplateGenerator(WithExtraFormaterByExt(upperFormater), WithWriteFile(sink.WriteFile))
+
+		err := tg.GenerateFile(
+			map[string]string{"greeting.custom": "Hello, {{.Name}}!"},
+			"greeting.custom", "", "out/greeting.custom", struct{ Name string }{Name: "World"})
+		require.NoError(t, err)
+
+		require.Equal(t, "HELLO, WORLD!", sink.Files["out/greeting.custom"])
+	})
+
+	t.Run("DiffSink compares formatted content, not the raw rendered output", func(t *testing.T) {
+		upperFormater := FormaterByExtMap{
+			".custom": func(outputFile string) error {
+				data, err := os.ReadFile(outputFile)
+				if err != nil {
+					return err
+				}
+
+				return os.WriteFile(outputFile, []byte(strings.ToUpper(string(data))), 0o644)
+			},
+		}
+
+		testOutput := "./testoutput"
+		_ = os.MkdirAll(testOutput, os.ModePerm)
+
+		defer func() {
+			_ = os.RemoveAll(testOutput)
+		}()
+
+		outputFile := path.Join(testOutput, "greeting.custom")
+		require.NoError(t, os.WriteFile(outputFile, []byte("HELLO, WORLD!"), 0o644))
+
+		sink := NewDiffSink()
+
+		tg := NewTemplateGenerator(WithExtraFormaterByExt(upperFormater), WithWriteFile(sink.WriteFile))
+
+		err := tg.GenerateFile(
+			map[string]string{"greeting.custom": "Hello, {{.Name}}!"},
+			"greeting.custom", "", outputFile, struct{ Name string }{Name: "World"})
+		require.NoError(t, err)
+
+		require.Equal(t, []DiffEntry{{Path: outputFile, Exists: true, Changed: false}}, sink.Changes)
+	})
+}
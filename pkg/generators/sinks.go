@@ -0,0 +1,98 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// MapSink collects rendered output in memory instead of writing it to disk, keyed by output path. Useful in
+// tests, and for callers that want to post-process generated files before touching the filesystem.
+type MapSink struct {
+	mu    sync.Mutex
+	Files map[string]string
+}
+
+// NewMapSink creates an empty MapSink.
+func NewMapSink() *MapSink {
+	return &MapSink{Files: map[string]string{}}
+}
+
+// WriteFile implements WriteFile by recording data under path instead of writing it to disk.
+func (s *MapSink) WriteFile(path string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Files[path] = string(data)
+
+	return nil
+}
+
+// DryRunEntry describes a single file DryRunSink would have written.
+type DryRunEntry struct {
+	Path  string
+	Bytes int
+}
+
+// DryRunSink records the paths and byte counts that would be written, without touching the filesystem.
+type DryRunSink struct {
+	mu      sync.Mutex
+	Entries []DryRunEntry
+}
+
+// NewDryRunSink creates an empty DryRunSink.
+func NewDryRunSink() *DryRunSink {
+	return &DryRunSink{}
+}
+
+// WriteFile implements WriteFile by recording path and len(data) instead of writing to disk.
+func (s *DryRunSink) WriteFile(path string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Entries = append(s.Entries, DryRunEntry{Path: path, Bytes: len(data)})
+
+	return nil
+}
+
+// DiffEntry describes how a single output would change relative to what's currently on disk.
+type DiffEntry struct {
+	Path    string
+	Exists  bool
+	Changed bool
+}
+
+// DiffSink compares rendered output against the files already on disk, without writing anything, and records
+// which outputs would change.
+type DiffSink struct {
+	mu      sync.Mutex
+	Changes []DiffEntry
+}
+
+// NewDiffSink creates an empty DiffSink.
+func NewDiffSink() *DiffSink {
+	return &DiffSink{}
+}
+
+// WriteFile implements WriteFile by comparing data against the existing file at path, if any, and recording
+// whether it would change, instead of writing to disk.
+func (s *DiffSink) WriteFile(path string, data []byte) error {
+	existing, err := os.ReadFile(path)
+
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("%w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Changes = append(s.Changes, DiffEntry{
+		Path:    path,
+		Exists:  exists,
+		Changed: !exists || !bytes.Equal(existing, data),
+	})
+
+	return nil
+}